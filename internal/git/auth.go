@@ -0,0 +1,127 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+	ssh2 "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// AuthMethodType selects which credential source generateAuth should use to
+// build the transport.AuthMethod handed to go-git.
+type AuthMethodType int
+
+const (
+	// AuthMethodSSHKey authenticates with an in-memory SSH private key,
+	// optionally protected by a passphrase.
+	AuthMethodSSHKey AuthMethodType = iota
+	// AuthMethodSSHAgent authenticates against a running ssh-agent reachable
+	// through SSH_AUTH_SOCK.
+	AuthMethodSSHAgent
+	// AuthMethodHTTPSToken authenticates over HTTPS using a personal access
+	// token as the password.
+	AuthMethodHTTPSToken
+)
+
+// AuthConfig describes how InitRepo should authenticate against the remote.
+type AuthConfig struct {
+	Method AuthMethodType
+
+	// SSHKey and SSHKeyPassphrase are used when Method is AuthMethodSSHKey.
+	SSHKey           []byte
+	SSHKeyPassphrase string
+
+	// HTTPSUsername and HTTPSToken are used when Method is AuthMethodHTTPSToken.
+	HTTPSUsername string
+	HTTPSToken    string
+
+	// InsecureIgnoreHostKey disables host key verification entirely. It is
+	// meant as an explicit opt-in escape hatch, never the default.
+	InsecureIgnoreHostKey bool
+	// KnownHostsFile overrides the known_hosts file used to verify the
+	// remote host key. Defaults to ~/.ssh/known_hosts when empty.
+	KnownHostsFile string
+}
+
+// buildAuth turns an AuthConfig into the transport.AuthMethod go-git expects,
+// wiring up host key verification for the SSH-based methods.
+func buildAuth(cfg AuthConfig) (transport.AuthMethod, error) {
+	switch cfg.Method {
+	case AuthMethodHTTPSToken:
+		return &http.BasicAuth{
+			Username: cfg.HTTPSUsername,
+			Password: cfg.HTTPSToken,
+		}, nil
+	case AuthMethodSSHAgent:
+		auth, err := ssh.NewSSHAgentAuth("git")
+		if err != nil {
+			log.Error().Err(err).Msgf("Error connecting to ssh-agent")
+			return nil, err
+		}
+		callback, err := hostKeyCallback(cfg)
+		if err != nil {
+			return nil, err
+		}
+		auth.HostKeyCallback = callback
+		return auth, nil
+	default: // AuthMethodSSHKey
+		auth, err := ssh.NewPublicKeys("git", cfg.SSHKey, cfg.SSHKeyPassphrase)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error generating public key")
+			return nil, err
+		}
+		callback, err := hostKeyCallback(cfg)
+		if err != nil {
+			return nil, err
+		}
+		auth.HostKeyCallback = callback
+		return auth, nil
+	}
+}
+
+// hostKeyCallback builds the host key verification callback shared by the
+// SSH-based auth methods, defaulting to known_hosts unless the caller opted
+// into InsecureIgnoreHostKey.
+func hostKeyCallback(cfg AuthConfig) (ssh2.HostKeyCallback, error) {
+	if cfg.InsecureIgnoreHostKey {
+		return ssh2.InsecureIgnoreHostKey(), nil
+	}
+
+	knownHostsFile := cfg.KnownHostsFile
+	if knownHostsFile == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			log.Error().Err(err).Msgf("Error resolving home directory for known_hosts")
+			return nil, err
+		}
+		knownHostsFile = filepath.Join(home, ".ssh", "known_hosts")
+	}
+
+	callback, err := knownhosts.New(knownHostsFile)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error loading known_hosts file %v", knownHostsFile)
+		return nil, err
+	}
+	return callback, nil
+}
+
+// sshAgentFallback builds an ssh-agent backed AuthMethod so callers can retry
+// a rejected SSH key against whatever identities ssh-agent is holding.
+func sshAgentFallback(cfg AuthConfig) (transport.AuthMethod, error) {
+	auth, err := ssh.NewSSHAgentAuth("git")
+	if err != nil {
+		return nil, fmt.Errorf("ssh-agent fallback unavailable: %w", err)
+	}
+	callback, err := hostKeyCallback(cfg)
+	if err != nil {
+		return nil, err
+	}
+	auth.HostKeyCallback = callback
+	return auth, nil
+}
@@ -0,0 +1,307 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	goGit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// Config describes the target repository and how a Repository should
+// authenticate and batch commits against it.
+type Config struct {
+	Path     string
+	GitRepo  string
+	Auth     AuthConfig
+	QueueMin int
+	QueueMax int
+
+	// SparsePaths, when non-empty, restricts the checkout to these subtrees
+	// instead of the whole repository. Paths follow sparse-checkout syntax
+	// (e.g. "/some/dir/").
+	SparsePaths []string
+
+	// Signing configures GPG signing of commits made via CommitAndPush.
+	Signing SigningConfig
+
+	// Timeout bounds each individual network operation (a pull, or a single
+	// push attempt) so a stuck connection can't hang the caller forever. It
+	// applies per attempt, not to a push's retry loop as a whole — a push
+	// retried 5 times can take up to ~5x Timeout plus backoff sleeps. Zero
+	// means no timeout beyond what the caller's own context imposes.
+	Timeout time.Duration
+}
+
+// Repository wraps a single local/remote git repository pair, holding the
+// state (underlying go-git handle, auth, commit-batching counters) that used
+// to live in package-level globals. A Repository is safe for concurrent use;
+// go-git itself is not, so every call into it is serialized behind mu.
+type Repository struct {
+	mu sync.Mutex
+
+	repo    *goGit.Repository
+	auth    transport.AuthMethod
+	signKey *openpgp.Entity
+
+	cfg Config
+
+	untrackedFile int
+	commitQueue   int
+}
+
+// NewRepository builds a Repository and opens it against cfg.Path,
+// initiating or opening it as needed. It is the preferred entry point for new code;
+// InitRepo remains as a thin wrapper over it for existing callers.
+func NewRepository(ctx context.Context, cfg Config) (*Repository, error) {
+	r := &Repository{cfg: cfg}
+	if err := r.Open(ctx); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// withTimeout derives a child of ctx bounded by r.cfg.Timeout, if one is
+// configured. The returned cancel func must always be called.
+func (r *Repository) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.cfg.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, r.cfg.Timeout)
+}
+
+// Open initiates the repository at r.cfg.Path if it doesn't exist yet, or
+// opens it in place otherwise, wires up authentication, and pulls the
+// latest content.
+func (r *Repository) Open(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.untrackedFile = 0
+	r.commitQueue = calculateNewCommitQueue(r.cfg.QueueMin, r.cfg.QueueMax)
+
+	var err error
+	r.repo, err = goGit.PlainInit(r.cfg.Path, false)
+	if err != nil {
+		if err == goGit.ErrRepositoryAlreadyExists { // repo already initiated
+			r.repo, err = goGit.PlainOpen(r.cfg.Path)
+			if err != nil {
+				log.Error().Err(err).Msgf("Error opening existing repository")
+				return err
+			}
+			if err := r.generateAuth(); err != nil {
+				log.Error().Err(err).Msgf("Error generating key")
+				return err
+			}
+			if err := r.loadSigningKey(); err != nil {
+				log.Error().Err(err).Msgf("Error loading commit signing key")
+				return err
+			}
+
+			_ = r.pullIfExist(ctx)
+			return nil
+		}
+		log.Error().Err(err).Msgf("Error initiating repository")
+		return err
+	}
+
+	// repo need to be initiated
+	remoteURL := fmt.Sprintf("git@github.com:%v.git", r.cfg.GitRepo)
+	if r.cfg.Auth.Method == AuthMethodHTTPSToken {
+		remoteURL = fmt.Sprintf("https://github.com/%v.git", r.cfg.GitRepo)
+	}
+	_, err = r.repo.CreateRemote(&config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{remoteURL},
+	})
+	if err != nil {
+		log.Error().Err(err).Msgf("Error creating remote repository config")
+		return err
+	}
+
+	if err := r.generateAuth(); err != nil {
+		log.Error().Err(err).Msgf("Error generating key")
+		return err
+	}
+	if err := r.loadSigningKey(); err != nil {
+		log.Error().Err(err).Msgf("Error loading commit signing key")
+		return err
+	}
+
+	_ = r.pullIfExist(ctx)
+	return nil
+}
+
+func calculateNewCommitQueue(min, max int) int {
+	rand.Seed(time.Now().UnixNano())
+	return rand.Intn(max-min+1) + min
+}
+
+func (r *Repository) generateAuth() error {
+	var err error
+	r.auth, err = buildAuth(r.cfg.Auth)
+	return err
+}
+
+// pushOnce performs a single push attempt, falling back to ssh-agent keys
+// when the configured SSH key is rejected. It is the unit pushWithBackoff
+// retries, so it bounds itself with r.cfg.Timeout rather than having the
+// whole retry loop share one deadline.
+func (r *Repository) pushOnce(ctx context.Context) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	err := r.repo.PushContext(ctx, &goGit.PushOptions{Auth: r.auth})
+	if err != nil && r.cfg.Auth.Method == AuthMethodSSHKey && !errors.Is(err, goGit.NoErrAlreadyUpToDate) {
+		log.Warn().Err(err).Msgf("Push rejected with configured SSH key, retrying via ssh-agent")
+		if fallback, fallbackErr := sshAgentFallback(r.cfg.Auth); fallbackErr == nil {
+			err = r.repo.PushContext(ctx, &goGit.PushOptions{Auth: fallback})
+		}
+	}
+	if errors.Is(err, goGit.NoErrAlreadyUpToDate) {
+		return nil
+	}
+	return err
+}
+
+// Pull fetches and merges the latest content from origin. Unlike Open, which
+// ignores a failed pull, it surfaces the underlying error, for callers that
+// want to react to a failed pull.
+func (r *Repository) Pull(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.pullIfExist(ctx)
+}
+
+func (r *Repository) pullIfExist(ctx context.Context) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	workTree, err := r.repo.Worktree()
+	if err != nil {
+		log.Error().Err(err).Msgf("Error getting WorkTree")
+		return err
+	}
+
+	if len(r.cfg.SparsePaths) > 0 {
+		return r.pullSparse(ctx, workTree)
+	}
+
+	err = workTree.PullContext(ctx, &goGit.PullOptions{Auth: r.auth})
+	if err != nil && r.cfg.Auth.Method == AuthMethodSSHKey && !errors.Is(err, goGit.NoErrAlreadyUpToDate) {
+		log.Warn().Err(err).Msgf("Pull rejected with configured SSH key, retrying via ssh-agent")
+		if fallback, fallbackErr := sshAgentFallback(r.cfg.Auth); fallbackErr == nil {
+			err = workTree.PullContext(ctx, &goGit.PullOptions{Auth: fallback})
+		}
+	}
+	if err != nil {
+		log.Error().Err(err).Msgf("Error pulling the repository - Maybe it is empty?")
+		return err
+	}
+
+	return nil
+}
+
+// Status returns the current worktree status.
+func (r *Repository) Status() (goGit.Status, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	workTree, err := r.repo.Worktree()
+	if err != nil {
+		log.Error().Err(err).Msgf("Error getting WorkTree")
+		return nil, err
+	}
+	return workTree.Status()
+}
+
+// CommitAndPush batches up untracked changes and, once the randomized
+// commit queue threshold is reached, commits and pushes everything as
+// username/email.
+func (r *Repository) CommitAndPush(ctx context.Context, username, email string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// ctx itself is left unbounded here; pushOnce applies r.cfg.Timeout to
+	// each individual push attempt, not to the retry loop as a whole.
+	if pending, _ := r.loadPendingPush(); pending != nil {
+		log.Warn().Msgf("Retrying previously failed push of %v (attempt %v)", pending.HeadRef, pending.RetryCount+1)
+		if err := pushWithBackoff(ctx, r.pushOnce); err != nil {
+			if !isAuthError(err) {
+				r.savePendingPush(pending.HeadRef)
+			}
+			log.Error().Err(err).Msgf("Error retrying pending push")
+			return err
+		}
+		r.clearPendingPush()
+		r.untrackedFile = 0
+		r.commitQueue = calculateNewCommitQueue(r.cfg.QueueMin, r.cfg.QueueMax)
+	}
+
+	workTree, err := r.repo.Worktree()
+	if err != nil {
+		log.Error().Err(err).Msgf("Error getting WorkTree")
+		return err
+	}
+
+	status, err := workTree.Status()
+	if err != nil {
+		log.Error().Err(err).Msgf("Error retrieving status from workTree")
+		return err
+	}
+
+	if status.IsClean() { // nothing to do
+		log.Debug().Msg("Git status clean -> nothing to commit")
+		return nil
+	} else if r.untrackedFile < r.commitQueue {
+		log.Debug().Msgf("UntrackedFile %v < commitQueue %v", r.untrackedFile, r.commitQueue)
+		r.untrackedFile++
+	} else {
+		_, err = workTree.Add(".") // add everything to the staging area
+		if err != nil {
+			log.Error().Err(err).Msgf("Error adding new files to the staging area")
+			return err
+		}
+
+		_, err = workTree.Commit(fmt.Sprintf("New content from commit-reput - %v", time.Now().Format("2006-01-02 15:04:05")), &goGit.CommitOptions{
+			Author: &object.Signature{
+				Name:  username,
+				Email: email,
+				When:  time.Now(),
+			},
+			SignKey: r.signKey,
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("Error committing the staging area to the repository")
+			return err
+		}
+
+		if err := pushWithBackoff(ctx, r.pushOnce); err != nil {
+			if !isAuthError(err) {
+				if head, headErr := r.repo.Head(); headErr == nil {
+					r.savePendingPush(head.Hash().String())
+				}
+			}
+			log.Error().Err(err).Msgf("Error pushing the repository")
+			return err
+		}
+
+		log.Info().Msgf("Successfully pushed %v files  to the repository", r.untrackedFile)
+		r.untrackedFile = 0
+		r.commitQueue = calculateNewCommitQueue(r.cfg.QueueMin, r.cfg.QueueMax) // we reset the commitQueue to a new number of files
+	}
+
+	return err
+}
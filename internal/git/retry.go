@@ -0,0 +1,82 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+const (
+	pushRetryAttempts  = 5
+	pushRetryBaseDelay = time.Second
+	pushRetryMaxDelay  = 30 * time.Second
+)
+
+// isAuthError reports whether err is one go-git raises for rejected or
+// missing credentials, as opposed to a transient network failure. Auth
+// errors are not worth retrying.
+func isAuthError(err error) bool {
+	return errors.Is(err, transport.ErrAuthenticationRequired) ||
+		errors.Is(err, transport.ErrAuthorizationFailed) ||
+		errors.Is(err, transport.ErrInvalidAuthMethod)
+}
+
+// jitteredWait adds up to delay of random jitter on top of delay, so
+// several retrying callers don't all wake up at the same instant.
+func jitteredWait(delay time.Duration) time.Duration {
+	return delay + time.Duration(rand.Int63n(int64(delay)))
+}
+
+// nextDelay doubles delay, capped at max.
+func nextDelay(delay, max time.Duration) time.Duration {
+	delay *= 2
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
+
+// pushWithBackoff retries push against transient network errors with
+// exponential backoff and jitter, giving up immediately on auth errors.
+//
+// Callers in this package invoke it while holding Repository.mu, so a
+// flaky push can hold that lock for the whole retry sequence (seconds to
+// over a minute), blocking concurrent Pull/Status/CommitAndPush calls on
+// the same Repository for that long. This is deliberate: go-git is not
+// safe for concurrent use on the same Repository, so releasing mu mid-retry
+// would let another goroutine touch the worktree while a push is in
+// flight. Callers that can't tolerate the stall should serialize their own
+// CommitAndPush calls with a short Config.Timeout instead of relying on mu
+// to be cheap to wait on.
+func pushWithBackoff(ctx context.Context, push func(ctx context.Context) error) error {
+	return pushWithBackoffConfig(ctx, push, pushRetryAttempts, pushRetryBaseDelay, pushRetryMaxDelay)
+}
+
+// pushWithBackoffConfig is pushWithBackoff with its attempt count and delay
+// bounds broken out so tests can exercise the retry/backoff logic without
+// waiting on the real delays (15-30s of sleeping over a full retry run).
+func pushWithBackoffConfig(ctx context.Context, push func(ctx context.Context) error, attempts int, baseDelay, maxDelay time.Duration) error {
+	var err error
+	delay := baseDelay
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = push(ctx)
+		if err == nil || isAuthError(err) {
+			return err
+		}
+		if attempt == attempts {
+			break
+		}
+
+		select {
+		case <-time.After(jitteredWait(delay)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		delay = nextDelay(delay, maxDelay)
+	}
+	return err
+}
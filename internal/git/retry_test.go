@@ -0,0 +1,140 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+func TestIsAuthError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"authentication required", transport.ErrAuthenticationRequired, true},
+		{"authorization failed", transport.ErrAuthorizationFailed, true},
+		{"invalid auth method", transport.ErrInvalidAuthMethod, true},
+		{"network error", errors.New("connection reset by peer"), false},
+		{"wrapped auth error", errors.Join(errors.New("push failed"), transport.ErrAuthorizationFailed), true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isAuthError(c.err); got != c.want {
+				t.Errorf("isAuthError(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
+
+func TestJitteredWait(t *testing.T) {
+	delay := 100 * time.Millisecond
+	for i := 0; i < 50; i++ {
+		wait := jitteredWait(delay)
+		if wait < delay || wait >= 2*delay {
+			t.Fatalf("jitteredWait(%v) = %v, want in [%v, %v)", delay, wait, delay, 2*delay)
+		}
+	}
+}
+
+func TestNextDelay(t *testing.T) {
+	cases := []struct {
+		delay, max, want time.Duration
+	}{
+		{time.Second, 30 * time.Second, 2 * time.Second},
+		{20 * time.Second, 30 * time.Second, 30 * time.Second},
+		{30 * time.Second, 30 * time.Second, 30 * time.Second},
+	}
+	for _, c := range cases {
+		if got := nextDelay(c.delay, c.max); got != c.want {
+			t.Errorf("nextDelay(%v, %v) = %v, want %v", c.delay, c.max, got, c.want)
+		}
+	}
+}
+
+func TestPushWithBackoffConfig_SucceedsFirstTry(t *testing.T) {
+	calls := 0
+	err := pushWithBackoffConfig(context.Background(), func(ctx context.Context) error {
+		calls++
+		return nil
+	}, 5, time.Millisecond, 5*time.Millisecond)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %v, want 1", calls)
+	}
+}
+
+func TestPushWithBackoffConfig_RetriesThenSucceeds(t *testing.T) {
+	calls := 0
+	err := pushWithBackoffConfig(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection reset")
+		}
+		return nil
+	}, 5, time.Millisecond, 5*time.Millisecond)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %v, want 3", calls)
+	}
+}
+
+func TestPushWithBackoffConfig_ExhaustsAttempts(t *testing.T) {
+	calls := 0
+	wantErr := errors.New("connection reset")
+	err := pushWithBackoffConfig(context.Background(), func(ctx context.Context) error {
+		calls++
+		return wantErr
+	}, 3, time.Millisecond, 5*time.Millisecond)
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %v, want 3", calls)
+	}
+}
+
+func TestPushWithBackoffConfig_StopsImmediatelyOnAuthError(t *testing.T) {
+	calls := 0
+	err := pushWithBackoffConfig(context.Background(), func(ctx context.Context) error {
+		calls++
+		return transport.ErrAuthenticationRequired
+	}, 5, time.Millisecond, 5*time.Millisecond)
+
+	if !errors.Is(err, transport.ErrAuthenticationRequired) {
+		t.Fatalf("err = %v, want %v", err, transport.ErrAuthenticationRequired)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %v, want 1 (should not retry auth errors)", calls)
+	}
+}
+
+func TestPushWithBackoffConfig_RespectsContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+	}()
+
+	err := pushWithBackoffConfig(ctx, func(ctx context.Context) error {
+		calls++
+		return errors.New("connection reset")
+	}, 5, 50*time.Millisecond, 50*time.Millisecond)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
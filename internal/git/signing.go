@@ -0,0 +1,51 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// SigningConfig configures GPG commit signing. When Enabled, every commit
+// made through CommitAndPush is signed with PGPPrivateKey so it shows up as
+// "Verified" on GitHub.
+type SigningConfig struct {
+	Enabled bool
+
+	PGPPrivateKey []byte
+	PGPPassphrase string
+}
+
+// loadSigningKey decrypts r.cfg.Signing.PGPPrivateKey once, at Open time, so
+// CommitAndPush doesn't have to touch the passphrase on every commit.
+func (r *Repository) loadSigningKey() error {
+	if !r.cfg.Signing.Enabled {
+		return nil
+	}
+
+	entityList, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(r.cfg.Signing.PGPPrivateKey))
+	if err != nil {
+		return fmt.Errorf("parsing PGP signing key: %w", err)
+	}
+	if len(entityList) == 0 {
+		return fmt.Errorf("no PGP entity found in signing key")
+	}
+	entity := entityList[0]
+
+	if entity.PrivateKey != nil && entity.PrivateKey.Encrypted {
+		if err := entity.PrivateKey.Decrypt([]byte(r.cfg.Signing.PGPPassphrase)); err != nil {
+			return fmt.Errorf("decrypting PGP signing key: %w", err)
+		}
+	}
+	for _, subKey := range entity.Subkeys {
+		if subKey.PrivateKey != nil && subKey.PrivateKey.Encrypted {
+			if err := subKey.PrivateKey.Decrypt([]byte(r.cfg.Signing.PGPPassphrase)); err != nil {
+				return fmt.Errorf("decrypting PGP signing subkey: %w", err)
+			}
+		}
+	}
+
+	r.signKey = entity
+	return nil
+}
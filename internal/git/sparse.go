@@ -0,0 +1,83 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	goGit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// pullSparse fetches the latest content from origin and resets the worktree
+// to only r.cfg.SparsePaths, instead of the whole tree.
+//
+// Worktree.PullContext always resets the full tree (go-git has no sparse
+// pull), so it can't be reused here: a sparse Repository fetches and resets
+// itself via Worktree.ResetSparsely, the same primitive Worktree.Checkout
+// uses for CheckoutOptions.SparseCheckoutDirectories.
+func (r *Repository) pullSparse(ctx context.Context, workTree *goGit.Worktree) error {
+	remote, err := r.repo.Remote(goGit.DefaultRemoteName)
+	if err != nil {
+		return err
+	}
+
+	err = remote.FetchContext(ctx, &goGit.FetchOptions{Auth: r.auth})
+	if err != nil && r.cfg.Auth.Method == AuthMethodSSHKey && !errors.Is(err, goGit.NoErrAlreadyUpToDate) {
+		log.Warn().Err(err).Msgf("Fetch rejected with configured SSH key, retrying via ssh-agent")
+		if fallback, fallbackErr := sshAgentFallback(r.cfg.Auth); fallbackErr == nil {
+			err = remote.FetchContext(ctx, &goGit.FetchOptions{Auth: fallback})
+		}
+	}
+	if err != nil && !errors.Is(err, goGit.NoErrAlreadyUpToDate) {
+		log.Error().Err(err).Msgf("Error fetching the repository")
+		return err
+	}
+
+	head, err := r.repo.Reference(plumbing.HEAD, false)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error resolving HEAD")
+		return err
+	}
+	branch := head.Name()
+	if head.Type() != plumbing.HashReference {
+		branch = head.Target()
+	}
+
+	remoteRef, err := r.repo.Reference(plumbing.NewRemoteReferenceName(goGit.DefaultRemoteName, branch.Short()), true)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error resolving remote branch - Maybe it is empty?")
+		return err
+	}
+
+	if err := r.repo.Storer.SetReference(plumbing.NewHashReference(branch, remoteRef.Hash())); err != nil {
+		log.Error().Err(err).Msgf("Error updating local branch reference")
+		return err
+	}
+
+	// ResetSparsely only marks index entries outside dirs as skip-worktree,
+	// it doesn't populate the index in the first place - so the index needs
+	// the full tree in it before narrowing, same as go-git's own
+	// Clone-then-sparse-Checkout usage. The plain Reset below never touches
+	// the worktree itself when nothing has changed, so this costs an index
+	// rebuild, not a second file checkout.
+	resetOpts := &goGit.ResetOptions{Mode: goGit.MergeReset, Commit: remoteRef.Hash()}
+	if err := workTree.Reset(resetOpts); err != nil {
+		return err
+	}
+	return workTree.ResetSparsely(resetOpts, sparseCheckoutDirs(r.cfg.SparsePaths))
+}
+
+// sparseCheckoutDirs adapts r.cfg.SparsePaths - documented and written in
+// git's own sparse-checkout syntax ("/some/dir/") - to the bare,
+// no-leading-slash prefixes Worktree.ResetSparsely matches index entry names
+// against.
+func sparseCheckoutDirs(paths []string) []string {
+	dirs := make([]string, len(paths))
+	for i, p := range paths {
+		dirs[i] = strings.Trim(p, "/")
+	}
+	return dirs
+}
@@ -0,0 +1,77 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	goGit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+)
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestPullSparse_OnlyCheckOutsConfiguredPaths(t *testing.T) {
+	origin := t.TempDir()
+	runGit(t, origin, "init", ".")
+	for _, dir := range []string{"dirA", "dirB"} {
+		if err := os.MkdirAll(filepath.Join(origin, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(origin, dir, "file.txt"), []byte(dir), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runGit(t, origin, "add", ".")
+	runGit(t, origin, "commit", "-m", "initial")
+
+	clonePath := filepath.Join(t.TempDir(), "clone")
+	repo, err := goGit.PlainInit(clonePath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{origin}}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Repository{
+		repo: repo,
+		cfg:  Config{Path: clonePath, SparsePaths: []string{"/dirA/"}},
+	}
+
+	if err := r.pullIfExist(context.Background()); err != nil {
+		t.Fatalf("pullIfExist: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(clonePath, "dirA", "file.txt")); err != nil {
+		t.Errorf("dirA/file.txt should be checked out, got: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(clonePath, "dirB", "file.txt")); err == nil {
+		t.Error("dirB/file.txt should not be checked out under SparsePaths [\"/dirA/\"]")
+	}
+}
+
+func TestSparseCheckoutDirs_StripsSparseCheckoutSlashes(t *testing.T) {
+	got := sparseCheckoutDirs([]string{"/dirA/", "dirB", "/dirC"})
+	want := []string{"dirA", "dirB", "dirC"}
+	if len(got) != len(want) {
+		t.Fatalf("sparseCheckoutDirs() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("sparseCheckoutDirs()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package git
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// pendingPushState records a commit that landed locally but whose push to
+// origin exhausted its retries, so the next CommitAndPush call can finish
+// pushing it before doing any new work.
+type pendingPushState struct {
+	HeadRef    string    `json:"head_ref"`
+	RetryCount int       `json:"retry_count"`
+	SavedAt    time.Time `json:"saved_at"`
+}
+
+func (r *Repository) stateFilePath() string {
+	return filepath.Join(r.cfg.Path, ".git", "commit-reput-state.json")
+}
+
+// savePendingPush persists headRef as not-yet-pushed, bumping the retry
+// count if a pending push was already recorded.
+func (r *Repository) savePendingPush(headRef string) {
+	state := pendingPushState{HeadRef: headRef, RetryCount: 1, SavedAt: time.Now()}
+	if existing, err := r.loadPendingPush(); err == nil && existing != nil {
+		state.RetryCount = existing.RetryCount + 1
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error encoding pending push state")
+		return
+	}
+	if err := os.WriteFile(r.stateFilePath(), data, 0644); err != nil {
+		log.Error().Err(err).Msgf("Error persisting pending push state")
+	}
+}
+
+// loadPendingPush returns the previously saved pending push, or nil if none
+// is recorded.
+func (r *Repository) loadPendingPush() (*pendingPushState, error) {
+	data, err := os.ReadFile(r.stateFilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var state pendingPushState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *Repository) clearPendingPush() {
+	if err := os.Remove(r.stateFilePath()); err != nil && !os.IsNotExist(err) {
+		log.Error().Err(err).Msgf("Error clearing pending push state")
+	}
+}
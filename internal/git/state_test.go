@@ -0,0 +1,66 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestRepository(t *testing.T) *Repository {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatalf("creating .git dir: %v", err)
+	}
+	return &Repository{cfg: Config{Path: dir}}
+}
+
+func TestPendingPush_SaveLoadClear(t *testing.T) {
+	r := newTestRepository(t)
+
+	if pending, err := r.loadPendingPush(); err != nil || pending != nil {
+		t.Fatalf("loadPendingPush() = %v, %v, want nil, nil", pending, err)
+	}
+
+	r.savePendingPush("abc123")
+
+	pending, err := r.loadPendingPush()
+	if err != nil {
+		t.Fatalf("loadPendingPush() error = %v", err)
+	}
+	if pending == nil {
+		t.Fatal("loadPendingPush() = nil, want a pending push")
+	}
+	if pending.HeadRef != "abc123" {
+		t.Errorf("HeadRef = %q, want %q", pending.HeadRef, "abc123")
+	}
+	if pending.RetryCount != 1 {
+		t.Errorf("RetryCount = %v, want 1", pending.RetryCount)
+	}
+
+	r.clearPendingPush()
+
+	if pending, err := r.loadPendingPush(); err != nil || pending != nil {
+		t.Fatalf("loadPendingPush() after clear = %v, %v, want nil, nil", pending, err)
+	}
+}
+
+func TestPendingPush_SaveTwiceBumpsRetryCount(t *testing.T) {
+	r := newTestRepository(t)
+
+	r.savePendingPush("abc123")
+	r.savePendingPush("abc123")
+
+	pending, err := r.loadPendingPush()
+	if err != nil {
+		t.Fatalf("loadPendingPush() error = %v", err)
+	}
+	if pending.RetryCount != 2 {
+		t.Errorf("RetryCount = %v, want 2", pending.RetryCount)
+	}
+}
+
+func TestClearPendingPush_NoOpWhenNothingSaved(t *testing.T) {
+	r := newTestRepository(t)
+	r.clearPendingPush()
+}